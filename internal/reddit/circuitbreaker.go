@@ -0,0 +1,177 @@
+package reddit
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Client.send when the circuit breaker for the
+// request's endpoint is open, without ever calling the underlying HTTP
+// client.
+var ErrCircuitOpen = errors.New("reddit: circuit open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreakerPolicy configures per-endpoint circuit breaking.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive 5xx/timeout failures,
+	// within Window, that trips the breaker open.
+	FailureThreshold int
+
+	// Window bounds how long a failure streak can span; a gap longer than
+	// Window resets the streak back to zero.
+	Window time.Duration
+
+	// Cooldown is how long the breaker stays open before letting a single
+	// half-open trial request through.
+	Cooldown time.Duration
+}
+
+// DefaultCircuitBreakerPolicy trips after 5 consecutive failures inside a
+// 30s window, and waits 30s before trying again.
+var DefaultCircuitBreakerPolicy = CircuitBreakerPolicy{
+	FailureThreshold: 5,
+	Window:           30 * time.Second,
+	Cooldown:         30 * time.Second,
+}
+
+// WithCircuitBreakerPolicy overrides the Client's default circuit breaker
+// thresholds.
+func WithCircuitBreakerPolicy(policy CircuitBreakerPolicy) ClientOption {
+	return func(c *Client) {
+		c.circuitBreakerPolicy = policy
+	}
+}
+
+// circuitBreaker is a per-endpoint closed/open/half-open breaker.
+type circuitBreaker struct {
+	policy CircuitBreakerPolicy
+
+	mu            sync.Mutex
+	state         circuitState
+	failures      int
+	lastFailure   time.Time
+	openedAt      time.Time
+	trialInFlight bool
+}
+
+func newCircuitBreaker(policy CircuitBreakerPolicy) *circuitBreaker {
+	return &circuitBreaker{policy: policy, state: circuitClosed}
+}
+
+// allow reports whether a request may proceed. An open breaker transitions
+// to half-open and allows exactly one trial request through once Cooldown
+// has elapsed.
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if time.Since(cb.openedAt) < cb.policy.Cooldown || cb.trialInFlight {
+			return false
+		}
+		cb.state = circuitHalfOpen
+		cb.trialInFlight = true
+		return true
+	case circuitHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+func (cb *circuitBreaker) recordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = 0
+	cb.trialInFlight = false
+	cb.state = circuitClosed
+}
+
+func (cb *circuitBreaker) recordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == circuitHalfOpen {
+		cb.trialInFlight = false
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+		return
+	}
+
+	if time.Since(cb.lastFailure) > cb.policy.Window {
+		cb.failures = 0
+	}
+	cb.failures++
+	cb.lastFailure = time.Now()
+
+	if cb.failures >= cb.policy.FailureThreshold {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// isHalfOpen reports whether cb is currently running its one half-open
+// trial request.
+func (cb *circuitBreaker) isHalfOpen() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.state == circuitHalfOpen
+}
+
+// breakerFor returns the circuit breaker for key, creating it on first use.
+func (rc *Client) breakerFor(key string) *circuitBreaker {
+	rc.breakersMu.Lock()
+	defer rc.breakersMu.Unlock()
+
+	cb, ok := rc.breakers[key]
+	if !ok {
+		cb = newCircuitBreaker(rc.circuitBreakerPolicy)
+		rc.breakers[key] = cb
+	}
+	return cb
+}
+
+// breakerKey identifies the upstream endpoint a request targets, for
+// per-endpoint circuit breaking. It's derived straight from the outgoing
+// request's path rather than the request's (optional, rarely-set) tags, so
+// e.g. SubredditNew and AboutInfo always land in different breakers.
+func breakerKey(req *http.Request) string {
+	return req.URL.Path
+}
+
+// recordBreakerOutcome updates cb based on the result of a request: a nil
+// error (a 200 response) counts as a success, a 5xx or any transport error
+// (timeouts included) as a failure, and any other status (4xx) is left
+// neutral since it says nothing about Reddit's own health.
+// The one exception is a half-open trial: it must always resolve one way or
+// another, or the breaker would wedge in half-open forever the moment a
+// trial happens to land on a routine 4xx (a deleted post, a private
+// subreddit, ...). A 4xx during the trial at least proves the endpoint is
+// reachable, so it's treated as a success for breaker purposes.
+func recordBreakerOutcome(cb *circuitBreaker, err error) {
+	if err == nil {
+		cb.recordSuccess()
+		return
+	}
+
+	if se, ok := err.(ServerError); ok && se.StatusCode < 500 {
+		if cb.isHalfOpen() {
+			cb.recordSuccess()
+		}
+		return
+	}
+
+	cb.recordFailure()
+}
@@ -0,0 +1,117 @@
+package reddit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTripsAfterThreshold(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 3, Window: time.Minute, Cooldown: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if !cb.allow() {
+			t.Fatalf("allow() = false before threshold reached")
+		}
+		cb.recordFailure()
+	}
+
+	if !cb.allow() {
+		t.Fatalf("allow() = false before threshold reached")
+	}
+	cb.recordFailure()
+
+	if cb.allow() {
+		t.Fatalf("allow() = true, want false once the breaker has tripped open")
+	}
+}
+
+func TestCircuitBreakerHalfOpenAfterCooldown(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1, Window: time.Minute, Cooldown: time.Millisecond})
+
+	cb.recordFailure()
+	if cb.allow() {
+		t.Fatalf("allow() = true immediately after tripping, want false during cooldown")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if !cb.allow() {
+		t.Fatalf("allow() = false after cooldown elapsed, want a half-open trial")
+	}
+	if cb.allow() {
+		t.Fatalf("allow() = true for a second concurrent half-open trial, want only one in flight")
+	}
+}
+
+func TestCircuitBreakerClosesOnHalfOpenSuccess(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1, Window: time.Minute, Cooldown: time.Millisecond})
+
+	cb.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+
+	if !cb.allow() {
+		t.Fatalf("allow() = false, want a half-open trial to be let through")
+	}
+	cb.recordSuccess()
+
+	if !cb.allow() {
+		t.Fatalf("allow() = false after a successful half-open trial, want closed")
+	}
+}
+
+func TestCircuitBreakerReopensOnHalfOpenFailure(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1, Window: time.Minute, Cooldown: time.Millisecond})
+
+	cb.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+
+	if !cb.allow() {
+		t.Fatalf("allow() = false, want a half-open trial to be let through")
+	}
+	cb.recordFailure()
+
+	if cb.allow() {
+		t.Fatalf("allow() = true immediately after a half-open trial failed, want open again")
+	}
+}
+
+func TestRecordBreakerOutcomeIgnores4xx(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1, Window: time.Minute, Cooldown: time.Minute})
+
+	recordBreakerOutcome(cb, ServerError{StatusCode: 404})
+	if !cb.allow() {
+		t.Fatalf("a 404 tripped the breaker, want 4xx to be left neutral")
+	}
+
+	recordBreakerOutcome(cb, ServerError{StatusCode: 503})
+	if cb.allow() {
+		t.Fatalf("a 503 didn't trip the breaker, want 5xx to count as a failure")
+	}
+}
+
+func TestRecordBreakerOutcomeResolvesHalfOpenOn4xx(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1, Window: time.Minute, Cooldown: time.Millisecond})
+
+	cb.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+
+	if !cb.allow() {
+		t.Fatalf("allow() = false, want a half-open trial to be let through")
+	}
+
+	recordBreakerOutcome(cb, ServerError{StatusCode: 404})
+
+	if !cb.allow() {
+		t.Fatalf("allow() = false after a 4xx resolved the half-open trial, want closed and recovered")
+	}
+}
+
+func TestRecordBreakerOutcomeCountsTransportErrors(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1, Window: time.Minute, Cooldown: time.Minute})
+
+	recordBreakerOutcome(cb, errors.New("connection reset"))
+	if cb.allow() {
+		t.Fatalf("a transport error didn't trip the breaker")
+	}
+}
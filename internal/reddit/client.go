@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-go/statsd"
@@ -26,13 +27,18 @@ const (
 )
 
 type Client struct {
-	id     string
-	secret string
-	client *http.Client
-	tracer *httptrace.ClientTrace
-	pool   *fastjson.ParserPool
-	statsd statsd.ClientInterface
-	redis  *redis.Client
+	id          string
+	secret      string
+	client      *http.Client
+	tracer      *httptrace.ClientTrace
+	pool        *fastjson.ParserPool
+	statsd      statsd.ClientInterface
+	redis       *redis.Client
+	retryPolicy RetryPolicy
+
+	circuitBreakerPolicy CircuitBreakerPolicy
+	breakersMu           sync.Mutex
+	breakers             map[string]*circuitBreaker
 }
 
 type RateLimitingInfo struct {
@@ -43,12 +49,6 @@ type RateLimitingInfo struct {
 	Timestamp string
 }
 
-var backoffSchedule = []time.Duration{
-	4 * time.Second,
-	8 * time.Second,
-	16 * time.Second,
-}
-
 func SplitID(id string) (string, string) {
 	if parts := strings.Split(id, "_"); len(parts) == 2 {
 		return parts[0], parts[1]
@@ -72,7 +72,7 @@ func PostIDFromContext(context string) string {
 	return ""
 }
 
-func NewClient(id, secret string, statsd statsd.ClientInterface, redis *redis.Client, connLimit int) *Client {
+func NewClient(id, secret string, statsd statsd.ClientInterface, redis *redis.Client, connLimit int, opts ...ClientOption) *Client {
 	tracer := &httptrace.ClientTrace{
 		GotConn: func(info httptrace.GotConnInfo) {
 			if info.Reused {
@@ -98,15 +98,24 @@ func NewClient(id, secret string, statsd statsd.ClientInterface, redis *redis.Cl
 
 	pool := &fastjson.ParserPool{}
 
-	return &Client{
-		id,
-		secret,
-		client,
-		tracer,
-		pool,
-		statsd,
-		redis,
+	c := &Client{
+		id:                   id,
+		secret:               secret,
+		client:               client,
+		tracer:               tracer,
+		pool:                 pool,
+		statsd:               statsd,
+		redis:                redis,
+		retryPolicy:          DefaultRetryPolicy,
+		circuitBreakerPolicy: DefaultCircuitBreakerPolicy,
+		breakers:             make(map[string]*circuitBreaker),
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 type AuthenticatedClient struct {
@@ -115,6 +124,15 @@ type AuthenticatedClient struct {
 	redditId     string
 	refreshToken string
 	accessToken  string
+
+	// tokenSource, when set, supersedes accessToken: requests resolve a
+	// fresh token from it before sending and transparently refresh through
+	// it on a 401.
+	tokenSource *TokenSource
+
+	// infoCoalescer, when set via EnableAboutInfoCoalescing, batches
+	// concurrent AboutInfo calls into fewer /api/info requests.
+	infoCoalescer *aboutInfoCoalescer
 }
 
 func (rc *Client) NewAuthenticatedClient(redditId, refreshToken, accessToken string) *AuthenticatedClient {
@@ -122,13 +140,35 @@ func (rc *Client) NewAuthenticatedClient(redditId, refreshToken, accessToken str
 		panic("requires a redditId")
 	}
 
-	return &AuthenticatedClient{rc, redditId, refreshToken, accessToken}
+	return &AuthenticatedClient{Client: rc, redditId: redditId, refreshToken: refreshToken, accessToken: accessToken}
 }
 
-func (rc *Client) doRequest(r *Request) ([]byte, *RateLimitingInfo, error) {
+// NewAuthenticatedClientWithTokenSource builds an AuthenticatedClient whose
+// access token is resolved lazily from ts instead of being threaded through
+// by the caller. ts is responsible for obtaining and caching the token, and
+// for rotating refreshToken via its TokenStore.
+func (rc *Client) NewAuthenticatedClientWithTokenSource(redditId string, ts *TokenSource) *AuthenticatedClient {
+	if redditId == "" {
+		panic("requires a redditId")
+	}
+
+	return &AuthenticatedClient{Client: rc, redditId: redditId, tokenSource: ts}
+}
+
+// send performs the HTTP round-trip for r and classifies the result, but
+// leaves the response body for the caller to consume (buffered or
+// streamed). On any non-200 status or transport error, the body is closed
+// here and no *http.Response is returned.
+func (rc *Client) send(r *Request) (*http.Response, *RateLimitingInfo, time.Duration, error) {
 	req, err := r.HTTPRequest()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
+	}
+
+	breaker := rc.breakerFor(breakerKey(req))
+	if !breaker.allow() {
+		_ = rc.statsd.Incr("reddit.api.circuit.open", r.tags, 0.1)
+		return nil, nil, 0, ErrCircuitOpen
 	}
 
 	req = req.WithContext(httptrace.WithClientTrace(req.Context(), rc.tracer))
@@ -141,13 +181,13 @@ func (rc *Client) doRequest(r *Request) ([]byte, *RateLimitingInfo, error) {
 	_ = rc.statsd.Histogram("reddit.api.latency", float64(time.Since(start).Milliseconds()), r.tags, 0.1)
 
 	if err != nil {
+		recordBreakerOutcome(breaker, err)
 		_ = rc.statsd.Incr("reddit.api.errors", r.tags, 0.1)
 		if strings.Contains(err.Error(), "http2: timeout awaiting response headers") {
-			return nil, nil, ErrTimeout
+			return nil, nil, 0, ErrTimeout
 		}
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
-	defer resp.Body.Close()
 
 	rli := &RateLimitingInfo{Present: false}
 	if resp.Header.Get(RateLimitRemainingHeader) != "" {
@@ -159,40 +199,96 @@ func (rc *Client) doRequest(r *Request) ([]byte, *RateLimitingInfo, error) {
 	}
 
 	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
+		recordBreakerOutcome(breaker, ServerError{resp.StatusCode})
 		_ = rc.statsd.Incr("reddit.api.errors", r.tags, 0.1)
-		return nil, rli, ServerError{resp.StatusCode}
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		return nil, rli, retryAfter, ServerError{resp.StatusCode}
 	}
 
+	recordBreakerOutcome(breaker, nil)
+
+	return resp, rli, 0, nil
+}
+
+func (rc *Client) doRequest(r *Request) ([]byte, *RateLimitingInfo, time.Duration, error) {
+	resp, rli, retryAfter, err := rc.send(r)
+	if err != nil {
+		return nil, rli, retryAfter, err
+	}
+	defer resp.Body.Close()
+
 	bb, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		_ = rc.statsd.Incr("reddit.api.errors", r.tags, 0.1)
-		return nil, rli, err
+		return nil, rli, 0, err
 	}
-	return bb, rli, nil
+	return bb, rli, 0, nil
 }
 
-func (rac *AuthenticatedClient) request(r *Request, rh ResponseHandler, empty interface{}) (interface{}, error) {
-	if rac.isRateLimited() {
-		return nil, ErrRateLimited
+// withRetry runs do, which performs a single attempt of r against Reddit,
+// acquiring a rate limit token first and retrying per rac.retryPolicy on
+// retryable failures. It centralizes the acquire/retry/markRateLimited
+// bookkeeping shared by the buffered and streaming request paths.
+func (rac *AuthenticatedClient) withRetry(r *Request, do func() (*RateLimitingInfo, time.Duration, error)) error {
+	ctx := context.Background()
+	if req, herr := r.HTTPRequest(); herr == nil {
+		ctx = req.Context()
+	}
+
+	if rac.tokenSource != nil {
+		tok, err := rac.tokenSource.Token(ctx, rac.redditId)
+		if err != nil {
+			return err
+		}
+		r.SetToken(tok)
+	}
+
+	if err := rac.Acquire(ctx, rac.redditId, true); err != nil {
+		return err
 	}
 
 	rac.logRequest()
-	bb, rli, err := rac.doRequest(r)
+	rli, retryAfter, err := do()
 
-	if err != nil && r.retry {
-		for _, backoff := range backoffSchedule {
-			done := make(chan struct{})
+	if rac.tokenSource != nil && isUnauthorized(err) {
+		tok, rerr := rac.tokenSource.Refresh(ctx, rac.redditId)
+		if rerr != nil {
+			return rerr
+		}
+		r.SetToken(tok)
 
-			time.AfterFunc(backoff, func() {
-				_ = rac.statsd.Incr("reddit.api.retries", r.tags, 0.1)
-				rac.logRequest()
-				bb, rli, err = rac.doRequest(r)
-				done <- struct{}{}
-			})
+		if err := rac.Acquire(ctx, rac.redditId, true); err != nil {
+			return err
+		}
+
+		rac.logRequest()
+		rli, retryAfter, err = do()
+	}
 
-			<-done
+	if r.retry && isRetryable(err) {
+		for attempt := 0; attempt < rac.retryPolicy.MaxAttempts; attempt++ {
+			delay := rac.retryPolicy.delay(attempt, retryAfter)
 
-			if err == nil {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+
+			if err := rac.Acquire(ctx, rac.redditId, true); err != nil {
+				return err
+			}
+
+			tags := append(append([]string{}, r.tags...), fmt.Sprintf("attempt:%d", attempt+1))
+			_ = rac.statsd.Incr("reddit.api.retries", tags, 0.1)
+
+			rac.logRequest()
+			rli, retryAfter, err = do()
+
+			if !isRetryable(err) {
 				break
 			}
 		}
@@ -201,11 +297,26 @@ func (rac *AuthenticatedClient) request(r *Request, rh ResponseHandler, empty in
 	if err != nil {
 		_ = rac.statsd.Incr("reddit.api.errors", r.tags, 0.1)
 		if strings.Contains(err.Error(), "http2: timeout awaiting response headers") {
-			return nil, ErrTimeout
+			return ErrTimeout
 		}
+		return err
+	}
+
+	return rac.markRateLimited(rli)
+}
+
+func (rac *AuthenticatedClient) request(r *Request, rh ResponseHandler, empty interface{}) (interface{}, error) {
+	var bb []byte
+
+	err := rac.withRetry(r, func() (*RateLimitingInfo, time.Duration, error) {
+		var rli *RateLimitingInfo
+		var retryAfter time.Duration
+		var derr error
+		bb, rli, retryAfter, derr = rac.doRequest(r)
+		return rli, retryAfter, derr
+	})
+	if err != nil {
 		return nil, err
-	} else {
-		rac.markRateLimited(rli)
 	}
 
 	if r.emptyResponseBytes > 0 && len(bb) == r.emptyResponseBytes {
@@ -231,16 +342,12 @@ func (rac *AuthenticatedClient) logRequest() error {
 	return rac.redis.HIncrBy(context.Background(), "reddit:requests", rac.redditId, 1).Err()
 }
 
-func (rac *AuthenticatedClient) isRateLimited() bool {
-	if rac.redditId == SkipRateLimiting {
-		return false
-	}
-
-	key := fmt.Sprintf("reddit:%s:ratelimited", rac.redditId)
-	_, err := rac.redis.Get(context.Background(), key).Result()
-	return err != redis.Nil
-}
-
+// markRateLimited is a reactive safety net: when Reddit's own
+// x-ratelimit-remaining header reports we're below RequestRemainingBuffer,
+// forcibly drain the account's token bucket to zero so the next Acquire call
+// waits for the bucket to refill a token, rather than trusting our own
+// bucket math. The wait follows the bucket's refill rate; Reddit's reported
+// reset time is not used.
 func (rac *AuthenticatedClient) markRateLimited(rli *RateLimitingInfo) error {
 	if rac.redditId == SkipRateLimiting {
 		return ErrRequiresRedditId
@@ -256,19 +363,15 @@ func (rac *AuthenticatedClient) markRateLimited(rli *RateLimitingInfo) error {
 
 	_ = rac.statsd.Incr("reddit.api.ratelimit", nil, 1.0)
 
-	key := fmt.Sprintf("reddit:%s:ratelimited", rac.redditId)
-	duration := time.Duration(rli.Reset) * time.Second
-	info := fmt.Sprintf("%+v", *rli)
-
 	if rli.Used > 2000 {
+		info := fmt.Sprintf("%+v", *rli)
 		_, err := rac.redis.HSet(context.Background(), "reddit:ratelimited:crazy", rac.redditId, info).Result()
 		if err != nil {
 			return err
 		}
 	}
 
-	_, err := rac.redis.SetEX(context.Background(), key, info, duration).Result()
-	return err
+	return rac.drainBucket(context.Background(), rac.redditId)
 }
 
 func (rac *AuthenticatedClient) RefreshTokens() (*RefreshTokenResponse, error) {
@@ -301,7 +404,19 @@ func (rac *AuthenticatedClient) RefreshTokens() (*RefreshTokenResponse, error) {
 	return ret, nil
 }
 
+// AboutInfo fetches info for a single fullname. If EnableAboutInfoCoalescing
+// has been called and no opts are given, fullname is instead folded into the
+// next batched /api/info call so that many concurrent callers share one
+// request; a call with opts always makes its own request.
 func (rac *AuthenticatedClient) AboutInfo(fullname string, opts ...RequestOption) (*ListingResponse, error) {
+	if rac.infoCoalescer != nil && len(opts) == 0 {
+		thing, err := rac.infoCoalescer.enqueue(fullname)
+		if err != nil {
+			return nil, err
+		}
+		return &ListingResponse{Things: []Thing{thing}}, nil
+	}
+
 	opts = append([]RequestOption{
 		WithMethod("GET"),
 		WithToken(rac.accessToken),
@@ -318,6 +433,25 @@ func (rac *AuthenticatedClient) AboutInfo(fullname string, opts ...RequestOption
 	return lr.(*ListingResponse), nil
 }
 
+// AboutInfoBatch fetches info for up to aboutInfoBatchMax fullnames in a
+// single /api/info call.
+func (rac *AuthenticatedClient) AboutInfoBatch(ids []string, opts ...RequestOption) (*ListingResponse, error) {
+	opts = append([]RequestOption{
+		WithMethod("GET"),
+		WithToken(rac.accessToken),
+		WithURL("https://oauth.reddit.com/api/info"),
+		WithQuery("id", strings.Join(ids, ",")),
+	}, opts...)
+	req := NewRequest(opts...)
+
+	lr, err := rac.request(req, NewListingResponse, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return lr.(*ListingResponse), nil
+}
+
 func (rac *AuthenticatedClient) UserPosts(user string, opts ...RequestOption) (*ListingResponse, error) {
 	url := fmt.Sprintf("https://oauth.reddit.com/u/%s/submitted", user)
 	opts = append([]RequestOption{
@@ -387,18 +521,28 @@ func (rac *AuthenticatedClient) subredditPosts(subreddit string, sort string, op
 	return lr.(*ListingResponse), nil
 }
 
+// SubredditHot buffers the full response and parses it with fastjson. For a
+// large pull, prefer SubredditHotStream, which decodes incrementally instead
+// of materializing the whole listing at once; this buffered form stays
+// intentionally unchanged so existing callers don't need to migrate.
 func (rac *AuthenticatedClient) SubredditHot(subreddit string, opts ...RequestOption) (*ListingResponse, error) {
 	return rac.subredditPosts(subreddit, "hot", opts...)
 }
 
+// SubredditTop is the buffered counterpart to SubredditTopStream; see
+// SubredditHot's doc comment for why both forms exist.
 func (rac *AuthenticatedClient) SubredditTop(subreddit string, opts ...RequestOption) (*ListingResponse, error) {
 	return rac.subredditPosts(subreddit, "top", opts...)
 }
 
+// SubredditNew is the buffered counterpart to SubredditNewStream; see
+// SubredditHot's doc comment for why both forms exist.
 func (rac *AuthenticatedClient) SubredditNew(subreddit string, opts ...RequestOption) (*ListingResponse, error) {
 	return rac.subredditPosts(subreddit, "new", opts...)
 }
 
+// MessageInbox is the buffered counterpart to MessageInboxStream; see
+// SubredditHot's doc comment for why both forms exist.
 func (rac *AuthenticatedClient) MessageInbox(opts ...RequestOption) (*ListingResponse, error) {
 	opts = append([]RequestOption{
 		WithTags([]string{"url:/api/v1/message/inbox"}),
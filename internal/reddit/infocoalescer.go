@@ -0,0 +1,145 @@
+package reddit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// aboutInfoBatchMax is the most fullnames Reddit permits in a single
+	// /api/info call.
+	aboutInfoBatchMax = 100
+
+	// aboutInfoCoalesceWindow is how long AboutInfo buffers calls before
+	// flushing a batch, once coalescing is enabled.
+	aboutInfoCoalesceWindow = 20 * time.Millisecond
+)
+
+// ErrNotFound is returned by a coalesced AboutInfo call when its fullname
+// didn't come back in the batched /api/info response.
+var ErrNotFound = errors.New("reddit: fullname not found in /api/info response")
+
+type aboutInfoRequest struct {
+	id     string
+	result chan aboutInfoResult
+}
+
+type aboutInfoResult struct {
+	thing Thing
+	err   error
+}
+
+// aboutInfoCoalescer batches concurrent AboutInfo calls into fewer
+// /api/info requests, fanning results back out by fullname.
+type aboutInfoCoalescer struct {
+	rac *AuthenticatedClient
+
+	mu      sync.Mutex
+	pending []aboutInfoRequest
+	timer   *time.Timer
+
+	// fetchBatch issues the batched /api/info call. It's a field rather
+	// than a direct call to rac.AboutInfoBatch so tests can stub it and
+	// drive enqueue/flush without making a real request to Reddit.
+	fetchBatch func(ids []string) (*ListingResponse, error)
+}
+
+func newAboutInfoCoalescer(rac *AuthenticatedClient) *aboutInfoCoalescer {
+	c := &aboutInfoCoalescer{rac: rac}
+	c.fetchBatch = c.rac.AboutInfoBatch
+	return c
+}
+
+// EnableAboutInfoCoalescing turns on request coalescing for AboutInfo calls
+// made through rac: calls are buffered for up to aboutInfoCoalesceWindow (or
+// until aboutInfoBatchMax accumulate) and issued as one AboutInfoBatch call.
+func (rac *AuthenticatedClient) EnableAboutInfoCoalescing() {
+	rac.infoCoalescer = newAboutInfoCoalescer(rac)
+}
+
+func (c *aboutInfoCoalescer) enqueue(fullname string) (Thing, error) {
+	result := make(chan aboutInfoResult, 1)
+
+	c.mu.Lock()
+	c.pending = append(c.pending, aboutInfoRequest{id: fullname, result: result})
+	flushNow := len(c.pending) >= aboutInfoBatchMax
+	if flushNow {
+		if c.timer != nil {
+			c.timer.Stop()
+			c.timer = nil
+		}
+	} else if c.timer == nil {
+		c.timer = time.AfterFunc(aboutInfoCoalesceWindow, c.flush)
+	}
+	c.mu.Unlock()
+
+	if flushNow {
+		c.flush()
+	}
+
+	r := <-result
+	return r.thing, r.err
+}
+
+func (c *aboutInfoCoalescer) flush() {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.timer = nil
+
+	// enqueue only flushes once it observes aboutInfoBatchMax pending
+	// entries, but concurrent callers can keep appending between that check
+	// and this lock. Cap what we actually send Reddit and push any overflow
+	// back onto pending for an immediate follow-up flush, rather than
+	// sending a request over Reddit's documented id limit.
+	batch, overflow := capBatch(batch, aboutInfoBatchMax)
+	c.pending = overflow
+	c.mu.Unlock()
+
+	if len(batch) > 0 {
+		c.flushBatch(batch)
+	}
+
+	if len(overflow) > 0 {
+		c.flush()
+	}
+}
+
+// capBatch splits batch into a head of at most max entries and whatever
+// remains as overflow, without allocating when batch already fits.
+func capBatch(batch []aboutInfoRequest, max int) (head, overflow []aboutInfoRequest) {
+	if len(batch) <= max {
+		return batch, nil
+	}
+	return batch[:max], batch[max:]
+}
+
+func (c *aboutInfoCoalescer) flushBatch(batch []aboutInfoRequest) {
+	ids := make([]string, len(batch))
+	for i, req := range batch {
+		ids[i] = req.id
+	}
+
+	lr, err := c.fetchBatch(ids)
+	if err != nil {
+		for _, req := range batch {
+			req.result <- aboutInfoResult{err: err}
+		}
+		return
+	}
+
+	byName := make(map[string]Thing, len(lr.Things))
+	for _, thing := range lr.Things {
+		byName[thing.Name] = thing
+	}
+
+	for _, req := range batch {
+		thing, ok := byName[req.id]
+		if !ok {
+			req.result <- aboutInfoResult{err: ErrNotFound}
+			continue
+		}
+		req.result <- aboutInfoResult{thing: thing}
+	}
+}
@@ -0,0 +1,158 @@
+package reddit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCapBatch(t *testing.T) {
+	makeBatch := func(n int) []aboutInfoRequest {
+		batch := make([]aboutInfoRequest, n)
+		for i := range batch {
+			batch[i] = aboutInfoRequest{id: string(rune('a' + i))}
+		}
+		return batch
+	}
+
+	t.Run("under the cap returns the batch unchanged", func(t *testing.T) {
+		batch := makeBatch(5)
+
+		head, overflow := capBatch(batch, 100)
+		if len(head) != 5 {
+			t.Fatalf("head = %d items, want 5", len(head))
+		}
+		if overflow != nil {
+			t.Fatalf("overflow = %v, want nil", overflow)
+		}
+	})
+
+	t.Run("over the cap splits at the limit", func(t *testing.T) {
+		batch := makeBatch(130)
+
+		head, overflow := capBatch(batch, 100)
+		if len(head) != 100 {
+			t.Fatalf("head = %d items, want 100", len(head))
+		}
+		if len(overflow) != 30 {
+			t.Fatalf("overflow = %d items, want 30", len(overflow))
+		}
+		if head[0].id != batch[0].id || overflow[0].id != batch[100].id {
+			t.Fatalf("capBatch reordered entries")
+		}
+	})
+}
+
+func TestAboutInfoCoalescerEnqueueFansOutByName(t *testing.T) {
+	c := newAboutInfoCoalescer(&AuthenticatedClient{})
+
+	var gotIDs []string
+	c.fetchBatch = func(ids []string) (*ListingResponse, error) {
+		gotIDs = append([]string{}, ids...)
+		things := make([]Thing, len(ids))
+		for i, id := range ids {
+			things[i] = Thing{Name: id}
+		}
+		return &ListingResponse{Things: things}, nil
+	}
+
+	ids := []string{"t3_a", "t3_b", "t1_c"}
+	results := make([]Thing, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			results[i], errs[i] = c.enqueue(id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("enqueue(%q) error = %v", ids[i], err)
+		}
+		if results[i].Name != ids[i] {
+			t.Fatalf("enqueue(%q) = %+v, want Name %q", ids[i], results[i], ids[i])
+		}
+	}
+	if len(gotIDs) != len(ids) {
+		t.Fatalf("fetchBatch got %d ids, want a single coalesced call with %d", len(gotIDs), len(ids))
+	}
+}
+
+func TestAboutInfoCoalescerEnqueueReturnsErrNotFound(t *testing.T) {
+	c := newAboutInfoCoalescer(&AuthenticatedClient{})
+	c.fetchBatch = func(ids []string) (*ListingResponse, error) {
+		return &ListingResponse{}, nil
+	}
+
+	if _, err := c.enqueue("t3_missing"); err != ErrNotFound {
+		t.Fatalf("enqueue() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestAboutInfoCoalescerEnqueuePropagatesFetchError(t *testing.T) {
+	c := newAboutInfoCoalescer(&AuthenticatedClient{})
+	wantErr := errors.New("boom")
+	c.fetchBatch = func(ids []string) (*ListingResponse, error) {
+		return nil, wantErr
+	}
+
+	if _, err := c.enqueue("t3_a"); err != wantErr {
+		t.Fatalf("enqueue() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestAboutInfoCoalescerFlushReflushesOverflowPastBatchMax(t *testing.T) {
+	c := newAboutInfoCoalescer(&AuthenticatedClient{})
+
+	var mu sync.Mutex
+	var calls [][]string
+	c.fetchBatch = func(ids []string) (*ListingResponse, error) {
+		mu.Lock()
+		calls = append(calls, append([]string{}, ids...))
+		mu.Unlock()
+
+		things := make([]Thing, len(ids))
+		for i, id := range ids {
+			things[i] = Thing{Name: id}
+		}
+		return &ListingResponse{Things: things}, nil
+	}
+
+	const n = aboutInfoBatchMax + 30
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.enqueue(string(rune('a' + i%26)))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("enqueue() call %d error = %v", i, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	total := 0
+	for _, ids := range calls {
+		if len(ids) > aboutInfoBatchMax {
+			t.Fatalf("fetchBatch called with %d ids, want <= %d", len(ids), aboutInfoBatchMax)
+		}
+		total += len(ids)
+	}
+	if total != n {
+		t.Fatalf("fetchBatch saw %d ids total across %d calls, want %d", total, len(calls), n)
+	}
+	if len(calls) < 2 {
+		t.Fatalf("fetchBatch called %d times, want at least 2 for an over-max batch (immediate flush + overflow re-flush)", len(calls))
+	}
+}
@@ -0,0 +1,152 @@
+package reddit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// RateLimitBucketCapacity mirrors Reddit's documented per-OAuth-client
+	// budget of 100 requests per minute.
+	RateLimitBucketCapacity = 100.0
+
+	// RateLimitRefillPerSecond is the steady-state refill rate that spreads
+	// RateLimitBucketCapacity evenly across a minute.
+	RateLimitRefillPerSecond = RateLimitBucketCapacity / 60.0
+)
+
+// tokenBucketScript atomically refills and decrements a per-redditId token
+// bucket stored as a Redis hash. It returns whether a token was granted, the
+// tokens remaining, and (when denied) how long the caller should wait before
+// trying again.
+var tokenBucketScript = redis.NewScript(`
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "timestamp")
+local tokens = tonumber(bucket[1])
+local timestamp = tonumber(bucket[2])
+
+local capacity = tonumber(ARGV[1])
+local refill_rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local requested = tonumber(ARGV[4])
+
+if tokens == nil then
+  tokens = capacity
+  timestamp = now
+end
+
+local elapsed = math.max(0, now - timestamp)
+tokens = math.min(capacity, tokens + elapsed * refill_rate)
+
+local allowed = 0
+local wait = 0
+
+if tokens >= requested then
+  tokens = tokens - requested
+  allowed = 1
+else
+  wait = (requested - tokens) / refill_rate
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "timestamp", tostring(now))
+redis.call("EXPIRE", KEYS[1], math.ceil(capacity / refill_rate) + 1)
+
+return {allowed, tostring(tokens), tostring(wait)}
+`)
+
+// BucketState is the result of a single token bucket acquisition attempt.
+type BucketState struct {
+	Remaining float64
+	Wait      time.Duration
+}
+
+func bucketKey(redditId string) string {
+	return fmt.Sprintf("reddit:%s:bucket", redditId)
+}
+
+func (rc *Client) tryAcquire(ctx context.Context, redditId string) (*BucketState, error) {
+	now := float64(time.Now().UnixNano()) / float64(time.Second)
+
+	res, err := tokenBucketScript.Run(ctx, rc.redis, []string{bucketKey(redditId)},
+		RateLimitBucketCapacity, RateLimitRefillPerSecond, now, 1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 3 {
+		return nil, fmt.Errorf("reddit: unexpected token bucket result %v", res)
+	}
+
+	allowed, _ := vals[0].(int64)
+	remaining := parseLuaFloat(vals[1])
+	wait := parseLuaFloat(vals[2])
+
+	state := &BucketState{
+		Remaining: remaining,
+		Wait:      time.Duration(wait * float64(time.Second)),
+	}
+
+	if allowed == 0 {
+		return state, ErrRateLimited
+	}
+
+	return state, nil
+}
+
+func parseLuaFloat(v interface{}) float64 {
+	s, ok := v.(string)
+	if !ok {
+		return 0
+	}
+
+	var f float64
+	_, _ = fmt.Sscanf(s, "%g", &f)
+	return f
+}
+
+// Acquire blocks until a token is available in redditId's bucket, emitting
+// wait-time and bucket-depth metrics along the way. When blocking is false,
+// it instead returns ErrRateLimited immediately if no token is available.
+// Acquire is a no-op for SkipRateLimiting.
+func (rc *Client) Acquire(ctx context.Context, redditId string, blocking bool) error {
+	if redditId == SkipRateLimiting {
+		return nil
+	}
+
+	for {
+		state, err := rc.tryAcquire(ctx, redditId)
+		if err == nil {
+			_ = rc.statsd.Gauge("reddit.api.bucket.depth", state.Remaining, []string{}, 1.0)
+			return nil
+		}
+
+		if err != ErrRateLimited {
+			return err
+		}
+
+		if !blocking {
+			return ErrRateLimited
+		}
+
+		_ = rc.statsd.Histogram("reddit.api.bucket.wait", float64(state.Wait.Milliseconds()), []string{}, 1.0)
+
+		timer := time.NewTimer(state.Wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// drainBucket forces redditId's token bucket to empty, used as a reactive
+// safety net when Reddit's own rate limit headers say we're nearly out of
+// budget despite what our local bucket thinks.
+func (rc *Client) drainBucket(ctx context.Context, redditId string) error {
+	now := float64(time.Now().UnixNano()) / float64(time.Second)
+	return rc.redis.HSet(ctx, bucketKey(redditId), "tokens", "0", "timestamp", fmt.Sprintf("%g", now)).Err()
+}
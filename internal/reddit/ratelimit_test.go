@@ -0,0 +1,114 @@
+package reddit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestRedisClient(t *testing.T) *redis.Client {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis.Run() failed: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	return redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+func newTestRateLimitClient(t *testing.T) *Client {
+	return &Client{redis: newTestRedisClient(t), statsd: &statsd.NoOpClient{}}
+}
+
+func TestTryAcquireGrantsFromAFullBucket(t *testing.T) {
+	rc := newTestRateLimitClient(t)
+
+	state, err := rc.tryAcquire(context.Background(), "acct")
+	if err != nil {
+		t.Fatalf("tryAcquire() error = %v", err)
+	}
+	if state.Remaining != RateLimitBucketCapacity-1 {
+		t.Fatalf("Remaining = %v, want %v", state.Remaining, RateLimitBucketCapacity-1)
+	}
+}
+
+func TestTryAcquireDeniesAnEmptyBucket(t *testing.T) {
+	rc := newTestRateLimitClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < int(RateLimitBucketCapacity); i++ {
+		if _, err := rc.tryAcquire(ctx, "acct"); err != nil {
+			t.Fatalf("tryAcquire() attempt %d error = %v", i, err)
+		}
+	}
+
+	state, err := rc.tryAcquire(ctx, "acct")
+	if err != ErrRateLimited {
+		t.Fatalf("tryAcquire() error = %v, want ErrRateLimited", err)
+	}
+	if state.Wait <= 0 {
+		t.Fatalf("Wait = %v, want > 0 once denied", state.Wait)
+	}
+}
+
+func TestAcquireNonBlockingReturnsErrRateLimited(t *testing.T) {
+	rc := newTestRateLimitClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < int(RateLimitBucketCapacity); i++ {
+		if err := rc.Acquire(ctx, "acct", true); err != nil {
+			t.Fatalf("Acquire() attempt %d error = %v", i, err)
+		}
+	}
+
+	if err := rc.Acquire(ctx, "acct", false); err != ErrRateLimited {
+		t.Fatalf("Acquire(blocking=false) error = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestAcquireSkipsRateLimitingSentinel(t *testing.T) {
+	rc := newTestRateLimitClient(t)
+
+	if err := rc.Acquire(context.Background(), SkipRateLimiting, false); err != nil {
+		t.Fatalf("Acquire(%q) error = %v, want nil", SkipRateLimiting, err)
+	}
+}
+
+func TestDrainBucketForcesSubsequentAcquireToWait(t *testing.T) {
+	rc := newTestRateLimitClient(t)
+	ctx := context.Background()
+
+	if err := rc.drainBucket(ctx, "acct"); err != nil {
+		t.Fatalf("drainBucket() error = %v", err)
+	}
+
+	if err := rc.Acquire(ctx, "acct", false); err != ErrRateLimited {
+		t.Fatalf("Acquire() after drainBucket() error = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestAcquireBlocksUntilRefill(t *testing.T) {
+	rc := newTestRateLimitClient(t)
+	ctx := context.Background()
+
+	if err := rc.drainBucket(ctx, "acct"); err != nil {
+		t.Fatalf("drainBucket() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- rc.Acquire(ctx, "acct", true)
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Acquire(blocking=true) returned early with err = %v, want it to wait for refill", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,109 @@
+package reddit
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how AuthenticatedClient.request backs off between
+// retries of a failed request.
+type RetryPolicy struct {
+	// MaxAttempts is the number of retries attempted after the initial
+	// request fails. A value of 0 disables retrying entirely.
+	MaxAttempts int
+
+	// Base is the starting delay used to compute the exponential backoff.
+	Base time.Duration
+
+	// Max is the ceiling applied to the computed backoff, before jitter.
+	Max time.Duration
+
+	// Jitter enables full-jitter backoff (a random delay between 0 and the
+	// computed backoff) instead of always sleeping the full duration.
+	Jitter bool
+}
+
+// DefaultRetryPolicy mirrors the previous hard-coded 4/8/16s backoff
+// schedule, but with full jitter applied.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Base:        4 * time.Second,
+	Max:         16 * time.Second,
+	Jitter:      true,
+}
+
+// delay computes the backoff for the given zero-indexed attempt, honoring
+// retryAfter (parsed from a Retry-After response header) when present. A
+// server-supplied retryAfter is still clamped to rp.Max: Retry-After comes
+// from an untrusted upstream response (and can be a far-future HTTP-date),
+// so honoring it verbatim would let a single bad or malicious value hang a
+// retry loop for hours, well past the "cap total wait" this policy exists
+// to enforce.
+func (rp RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if retryAfter > rp.Max {
+			return rp.Max
+		}
+		return retryAfter
+	}
+
+	d := rp.Base * time.Duration(int64(1)<<uint(attempt))
+	if d > rp.Max {
+		d = rp.Max
+	}
+
+	if !rp.Jitter {
+		return d
+	}
+
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// ClientOption configures optional behavior on a Client at construction
+// time.
+type ClientOption func(*Client)
+
+// WithRetryPolicy overrides the Client's default retry policy.
+func WithRetryPolicy(rp RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = rp
+	}
+}
+
+// isRetryable reports whether err warrants another attempt: transport-level
+// errors always do, while ServerError is only retried for 429 and 5xx
+// responses.
+func isRetryable(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case *StreamDecodeError:
+		return false
+	case ServerError:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+	default:
+		return err != ErrCircuitOpen
+	}
+}
+
+// parseRetryAfter parses a Retry-After header value in either of its two
+// permitted forms: a number of seconds, or an HTTP-date.
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(h); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,93 @@
+package reddit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelayHonorsRetryAfter(t *testing.T) {
+	rp := DefaultRetryPolicy
+
+	got := rp.delay(0, 5*time.Second)
+	if got != 5*time.Second {
+		t.Fatalf("delay = %v, want 5s when Retry-After is set", got)
+	}
+}
+
+func TestRetryPolicyDelayClampsRetryAfterToMax(t *testing.T) {
+	rp := RetryPolicy{MaxAttempts: 3, Base: time.Second, Max: 4 * time.Second, Jitter: false}
+
+	got := rp.delay(0, time.Hour)
+	if got != rp.Max {
+		t.Fatalf("delay = %v, want clamped to Max %v for an oversized Retry-After", got, rp.Max)
+	}
+}
+
+func TestRetryPolicyDelayCapsAtMax(t *testing.T) {
+	rp := RetryPolicy{MaxAttempts: 5, Base: time.Second, Max: 4 * time.Second, Jitter: false}
+
+	for attempt := 0; attempt < rp.MaxAttempts; attempt++ {
+		got := rp.delay(attempt, 0)
+		if got > rp.Max {
+			t.Fatalf("delay(%d) = %v, want <= %v", attempt, got, rp.Max)
+		}
+	}
+}
+
+func TestRetryPolicyDelayJitterIsBounded(t *testing.T) {
+	rp := RetryPolicy{MaxAttempts: 3, Base: time.Second, Max: 10 * time.Second, Jitter: true}
+
+	for i := 0; i < 100; i++ {
+		got := rp.delay(2, 0)
+		if got < 0 || got > rp.Max {
+			t.Fatalf("jittered delay = %v, want in [0, %v]", got, rp.Max)
+		}
+	}
+}
+
+func TestParseRetryAfterSeconds(t *testing.T) {
+	got := parseRetryAfter("120")
+	if got != 120*time.Second {
+		t.Fatalf("parseRetryAfter(\"120\") = %v, want 120s", got)
+	}
+}
+
+func TestParseRetryAfterHTTPDate(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC1123)
+	future = future[:len(future)-3] + "GMT"
+
+	got := parseRetryAfter(future)
+	if got <= 0 || got > time.Hour+time.Minute {
+		t.Fatalf("parseRetryAfter(%q) = %v, want a positive duration near 1h", future, got)
+	}
+}
+
+func TestParseRetryAfterEmpty(t *testing.T) {
+	if got := parseRetryAfter(""); got != 0 {
+		t.Fatalf("parseRetryAfter(\"\") = %v, want 0", got)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"429", ServerError{StatusCode: 429}, true},
+		{"500", ServerError{StatusCode: 500}, true},
+		{"404", ServerError{StatusCode: 404}, false},
+		{"circuit open", ErrCircuitOpen, false},
+		{"stream decode", &StreamDecodeError{Err: ErrTimeout}, false},
+		{"transport error", ErrTimeout, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isRetryable(tc.err); got != tc.want {
+				t.Fatalf("isRetryable(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
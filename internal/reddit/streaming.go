@@ -0,0 +1,184 @@
+package reddit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// emptyInboxResponseBytes is the exact byte length of Reddit's body for an
+// empty message inbox, mirroring the buffered MessageInbox's
+// WithEmptyResponseBytes(122).
+const emptyInboxResponseBytes = 122
+
+// StreamCallback is invoked once per Thing decoded from a streamed listing
+// response, in order, allowing callers to process and discard results
+// incrementally instead of buffering the whole listing in memory.
+//
+// Idempotency: a request is only retried when it fails before any Thing has
+// been decoded (a transport error, 429, or 5xx hitting send). Once decoding
+// has started, a failure partway through (wrapped in *StreamDecodeError) is
+// surfaced to the caller as-is and the request is not replayed, so cb is
+// never re-invoked for a Thing it has already seen.
+type StreamCallback func(Thing) error
+
+// StreamDecodeError wraps a failure that happened while decoding an
+// in-flight streamed response, after StreamCallback may have already fired
+// for a prefix of the listing. It is deliberately treated as non-retryable
+// by isRetryable, since replaying the request would re-deliver those items.
+type StreamDecodeError struct {
+	Err error
+}
+
+func (e *StreamDecodeError) Error() string {
+	return fmt.Sprintf("reddit: stream decode failed after partial delivery: %v", e.Err)
+}
+
+func (e *StreamDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeListingStream reads a Reddit listing response of the form
+// {"data": {"children": [...]}} from r, decoding each entry under
+// data.children one at a time and invoking cb for it.
+func decodeListingStream(r io.Reader, cb StreamCallback) error {
+	dec := json.NewDecoder(r)
+
+	if err := skipToKey(dec, "data"); err != nil {
+		return err
+	}
+
+	if err := skipToKey(dec, "children"); err != nil {
+		return err
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("reddit: expected children array, got %v", tok)
+	}
+
+	for dec.More() {
+		var thing Thing
+		if err := dec.Decode(&thing); err != nil {
+			return err
+		}
+		if err := cb(thing); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeListingStreamWithEmptyCheck is decodeListingStream, but first checks
+// for Reddit's known fixed-size empty-listing body (e.g. the 122-byte empty
+// inbox) so that body isn't fed to the listing decoder, which doesn't parse
+// it. When emptyBytes <= 0 the check is skipped entirely.
+func decodeListingStreamWithEmptyCheck(r io.Reader, emptyBytes int, cb StreamCallback) error {
+	if emptyBytes <= 0 {
+		return decodeListingStream(r, cb)
+	}
+
+	buf := make([]byte, emptyBytes+1)
+	n, err := io.ReadFull(r, buf)
+	if n == emptyBytes && (err == io.ErrUnexpectedEOF || err == io.EOF) {
+		return nil
+	}
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+
+	return decodeListingStream(io.MultiReader(bytes.NewReader(buf[:n]), r), cb)
+}
+
+// skipToKey advances dec token-by-token until it has just consumed the
+// first string token equal to key, leaving the decoder positioned to read
+// the token after it. It does not tell object keys from string values, and
+// it does not track nesting depth.
+func skipToKey(dec *json.Decoder, key string) error {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if s, ok := tok.(string); ok && s == key {
+			return nil
+		}
+	}
+}
+
+// doRequestStream is the streaming counterpart to doRequest: it decodes the
+// response body directly off the wire via decodeListingStream instead of
+// buffering it first.
+func (rc *Client) doRequestStream(r *Request, cb StreamCallback) (*RateLimitingInfo, time.Duration, error) {
+	resp, rli, retryAfter, err := rc.send(r)
+	if err != nil {
+		return rli, retryAfter, err
+	}
+	defer resp.Body.Close()
+
+	if err := decodeListingStreamWithEmptyCheck(resp.Body, r.emptyResponseBytes, cb); err != nil {
+		_ = rc.statsd.Incr("reddit.api.errors", r.tags, 0.1)
+		return rli, 0, &StreamDecodeError{Err: err}
+	}
+
+	return rli, 0, nil
+}
+
+// requestStream runs r through the same acquire/retry/rate-limit bookkeeping
+// as request, but streams the response into cb rather than returning a
+// parsed value.
+func (rac *AuthenticatedClient) requestStream(r *Request, cb StreamCallback) error {
+	return rac.withRetry(r, func() (*RateLimitingInfo, time.Duration, error) {
+		return rac.doRequestStream(r, cb)
+	})
+}
+
+func (rac *AuthenticatedClient) subredditPostsStream(subreddit string, sort string, cb StreamCallback, opts ...RequestOption) error {
+	url := fmt.Sprintf("https://oauth.reddit.com/r/%s/%s", subreddit, sort)
+	opts = append([]RequestOption{
+		WithMethod("GET"),
+		WithToken(rac.accessToken),
+		WithURL(url),
+	}, opts...)
+	req := NewRequest(opts...)
+
+	return rac.requestStream(req, cb)
+}
+
+// SubredditHotStream is the streaming counterpart to SubredditHot.
+func (rac *AuthenticatedClient) SubredditHotStream(subreddit string, cb StreamCallback, opts ...RequestOption) error {
+	return rac.subredditPostsStream(subreddit, "hot", cb, opts...)
+}
+
+// SubredditTopStream is the streaming counterpart to SubredditTop.
+func (rac *AuthenticatedClient) SubredditTopStream(subreddit string, cb StreamCallback, opts ...RequestOption) error {
+	return rac.subredditPostsStream(subreddit, "top", cb, opts...)
+}
+
+// SubredditNewStream is the streaming counterpart to SubredditNew.
+func (rac *AuthenticatedClient) SubredditNewStream(subreddit string, cb StreamCallback, opts ...RequestOption) error {
+	return rac.subredditPostsStream(subreddit, "new", cb, opts...)
+}
+
+// MessageInboxStream is the streaming counterpart to MessageInbox. Like
+// MessageInbox, it special-cases Reddit's fixed 122-byte empty-inbox body:
+// an empty inbox invokes cb zero times rather than erroring out of the
+// listing decoder.
+func (rac *AuthenticatedClient) MessageInboxStream(cb StreamCallback, opts ...RequestOption) error {
+	opts = append([]RequestOption{
+		WithTags([]string{"url:/api/v1/message/inbox"}),
+		WithMethod("GET"),
+		WithToken(rac.accessToken),
+		WithURL("https://oauth.reddit.com/message/inbox"),
+		WithEmptyResponseBytes(emptyInboxResponseBytes),
+	}, opts...)
+	req := NewRequest(opts...)
+
+	return rac.requestStream(req, cb)
+}
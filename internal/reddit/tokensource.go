@@ -0,0 +1,115 @@
+package reddit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// tokenRefreshEarly is subtracted from a token's reported lifetime so a
+// cached access token is treated as expired slightly before Reddit actually
+// rejects it.
+const tokenRefreshEarly = 30 * time.Second
+
+// TokenStore persists the refresh token Reddit hands back for a given
+// account, so TokenSource can pick up a rotated refresh token across
+// restarts instead of relying on a caller-supplied value growing stale.
+type TokenStore interface {
+	RefreshToken(ctx context.Context, redditId string) (string, error)
+	SetRefreshToken(ctx context.Context, redditId, refreshToken string) error
+}
+
+// TokenSource caches access tokens in Redis and collapses concurrent
+// refreshes for the same redditId into a single call to Reddit, so that
+// many workers sharing one account don't each trigger their own refresh.
+type TokenSource struct {
+	client *Client
+	store  TokenStore
+	group  singleflight.Group
+
+	// refreshTokens performs the actual OAuth refresh call. It's a field
+	// rather than a direct call to client.NewAuthenticatedClient(...).RefreshTokens()
+	// so tests can substitute a stub and exercise the caching/singleflight/
+	// rotation logic around it without making a real request to Reddit.
+	refreshTokens func(redditId, refreshToken string) (*RefreshTokenResponse, error)
+}
+
+// NewTokenSource builds a TokenSource backed by client's Redis connection
+// and store.
+func NewTokenSource(client *Client, store TokenStore) *TokenSource {
+	ts := &TokenSource{client: client, store: store}
+	ts.refreshTokens = func(redditId, refreshToken string) (*RefreshTokenResponse, error) {
+		return ts.client.NewAuthenticatedClient(redditId, refreshToken, "").RefreshTokens()
+	}
+	return ts
+}
+
+func accessTokenKey(redditId string) string {
+	return fmt.Sprintf("reddit:%s:access_token", redditId)
+}
+
+// Token returns a cached access token for redditId, refreshing it if it's
+// missing or expired.
+func (ts *TokenSource) Token(ctx context.Context, redditId string) (string, error) {
+	if cached, err := ts.client.redis.Get(ctx, accessTokenKey(redditId)).Result(); err == nil && cached != "" {
+		return cached, nil
+	}
+
+	return ts.Refresh(ctx, redditId)
+}
+
+// Refresh forces a token refresh for redditId, bypassing the cache.
+// Concurrent calls for the same redditId collapse into a single refresh.
+func (ts *TokenSource) Refresh(ctx context.Context, redditId string) (string, error) {
+	v, err, _ := ts.group.Do(redditId, func() (interface{}, error) {
+		return ts.refresh(ctx, redditId)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return v.(string), nil
+}
+
+func (ts *TokenSource) refresh(ctx context.Context, redditId string) (string, error) {
+	refreshToken, err := ts.store.RefreshToken(ctx, redditId)
+	if err != nil {
+		return "", err
+	}
+
+	rtr, err := ts.refreshTokens(redditId, refreshToken)
+	if err != nil {
+		return "", err
+	}
+
+	if rtr.RefreshToken != refreshToken {
+		if err := ts.store.SetRefreshToken(ctx, redditId, rtr.RefreshToken); err != nil {
+			return "", err
+		}
+	}
+
+	ttl := time.Duration(rtr.ExpiresIn) * time.Second
+	if ttl > tokenRefreshEarly {
+		ttl -= tokenRefreshEarly
+	}
+
+	if err := ts.client.redis.SetEX(ctx, accessTokenKey(redditId), rtr.AccessToken, ttl).Err(); err != nil {
+		return "", err
+	}
+
+	return rtr.AccessToken, nil
+}
+
+func isUnauthorized(err error) bool {
+	se, ok := err.(ServerError)
+	return ok && se.StatusCode == 401
+}
+
+// SetToken overwrites the bearer token a Request was built with. Used by
+// AuthenticatedClient.withRetry to apply a token from its TokenSource,
+// including when retrying a request after a transparent token refresh.
+func (r *Request) SetToken(token string) {
+	r.token = token
+}
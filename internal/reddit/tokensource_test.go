@@ -0,0 +1,162 @@
+package reddit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+)
+
+type fakeTokenStore struct {
+	mu              sync.Mutex
+	refreshToken    string
+	rotatedTo       []string
+	setRefreshCalls int
+}
+
+func (s *fakeTokenStore) RefreshToken(ctx context.Context, redditId string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refreshToken, nil
+}
+
+func (s *fakeTokenStore) SetRefreshToken(ctx context.Context, redditId, refreshToken string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rotatedTo = append(s.rotatedTo, refreshToken)
+	s.setRefreshCalls++
+	s.refreshToken = refreshToken
+	return nil
+}
+
+func newTestTokenSource(t *testing.T, store TokenStore) *TokenSource {
+	client := &Client{redis: newTestRedisClient(t), statsd: &statsd.NoOpClient{}}
+	return NewTokenSource(client, store)
+}
+
+func TestTokenSourceTokenReturnsCachedValueWithoutRefreshing(t *testing.T) {
+	ts := newTestTokenSource(t, &fakeTokenStore{refreshToken: "rt"})
+
+	var refreshCalls int32
+	ts.refreshTokens = func(redditId, refreshToken string) (*RefreshTokenResponse, error) {
+		atomic.AddInt32(&refreshCalls, 1)
+		return nil, errors.New("should not be called on a cache hit")
+	}
+
+	ctx := context.Background()
+	if err := ts.client.redis.SetEX(ctx, accessTokenKey("acct"), "cached-token", time.Minute).Err(); err != nil {
+		t.Fatalf("seeding cache failed: %v", err)
+	}
+
+	got, err := ts.Token(ctx, "acct")
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if got != "cached-token" {
+		t.Fatalf("Token() = %q, want %q", got, "cached-token")
+	}
+	if atomic.LoadInt32(&refreshCalls) != 0 {
+		t.Fatalf("refreshTokens called %d times, want 0 on a cache hit", refreshCalls)
+	}
+}
+
+func TestTokenSourceTokenRefreshesOnCacheMiss(t *testing.T) {
+	ts := newTestTokenSource(t, &fakeTokenStore{refreshToken: "rt"})
+	ts.refreshTokens = func(redditId, refreshToken string) (*RefreshTokenResponse, error) {
+		return &RefreshTokenResponse{AccessToken: "fresh-token", RefreshToken: refreshToken, ExpiresIn: 3600}, nil
+	}
+
+	ctx := context.Background()
+	got, err := ts.Token(ctx, "acct")
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if got != "fresh-token" {
+		t.Fatalf("Token() = %q, want %q", got, "fresh-token")
+	}
+
+	ttl, err := ts.client.redis.TTL(ctx, accessTokenKey("acct")).Result()
+	if err != nil {
+		t.Fatalf("TTL() error = %v", err)
+	}
+	want := 3600*time.Second - tokenRefreshEarly
+	if ttl <= 0 || ttl > want {
+		t.Fatalf("TTL = %v, want in (0, %v]", ttl, want)
+	}
+}
+
+func TestTokenSourceRefreshRotatesStoredRefreshToken(t *testing.T) {
+	store := &fakeTokenStore{refreshToken: "old-rt"}
+	ts := newTestTokenSource(t, store)
+	ts.refreshTokens = func(redditId, refreshToken string) (*RefreshTokenResponse, error) {
+		return &RefreshTokenResponse{AccessToken: "at", RefreshToken: "new-rt", ExpiresIn: 3600}, nil
+	}
+
+	if _, err := ts.Refresh(context.Background(), "acct"); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.setRefreshCalls != 1 || store.rotatedTo[0] != "new-rt" {
+		t.Fatalf("SetRefreshToken calls = %d rotatedTo = %v, want one call rotating to new-rt", store.setRefreshCalls, store.rotatedTo)
+	}
+}
+
+func TestTokenSourceRefreshLeavesUnrotatedRefreshTokenAlone(t *testing.T) {
+	store := &fakeTokenStore{refreshToken: "same-rt"}
+	ts := newTestTokenSource(t, store)
+	ts.refreshTokens = func(redditId, refreshToken string) (*RefreshTokenResponse, error) {
+		return &RefreshTokenResponse{AccessToken: "at", RefreshToken: refreshToken, ExpiresIn: 3600}, nil
+	}
+
+	if _, err := ts.Refresh(context.Background(), "acct"); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.setRefreshCalls != 0 {
+		t.Fatalf("SetRefreshToken called %d times, want 0 when the refresh token didn't rotate", store.setRefreshCalls)
+	}
+}
+
+func TestTokenSourceRefreshCollapsesConcurrentCallers(t *testing.T) {
+	ts := newTestTokenSource(t, &fakeTokenStore{refreshToken: "rt"})
+
+	var calls int32
+	release := make(chan struct{})
+	ts.refreshTokens = func(redditId, refreshToken string) (*RefreshTokenResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &RefreshTokenResponse{AccessToken: "at", RefreshToken: refreshToken, ExpiresIn: 3600}, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = ts.Refresh(context.Background(), "acct")
+		}(i)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Refresh() call %d error = %v", i, err)
+		}
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("refreshTokens called %d times, want exactly 1 for %d concurrent callers", calls, n)
+	}
+}